@@ -92,7 +92,7 @@ func CacheAntigravityReasoningReplayItemsBestEffort(ctx context.Context, modelNa
 		return written
 	}
 
-	cacheCleanupOnce.Do(startCacheCleanup)
+	startCacheCleanup()
 	now := time.Now()
 	antigravityReasoningReplayMu.Lock()
 	defer antigravityReasoningReplayMu.Unlock()
@@ -149,7 +149,7 @@ func GetAntigravityReasoningReplayItemsRequired(ctx context.Context, modelName,
 		return cloneAntigravityReasoningReplayItems(homeItems), true, nil
 	}
 
-	cacheCleanupOnce.Do(startCacheCleanup)
+	startCacheCleanup()
 	now := time.Now()
 	antigravityReasoningReplayMu.Lock()
 	defer antigravityReasoningReplayMu.Unlock()
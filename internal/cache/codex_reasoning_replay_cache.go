@@ -101,7 +101,7 @@ func CacheCodexReasoningReplayItemsBestEffort(ctx context.Context, modelName, se
 		return written
 	}
 
-	cacheCleanupOnce.Do(startCacheCleanup)
+	startCacheCleanup()
 	now := time.Now()
 	codexReasoningReplayMu.Lock()
 	defer codexReasoningReplayMu.Unlock()
@@ -170,7 +170,7 @@ func AppendCodexReasoningReplayItemsBestEffort(ctx context.Context, modelName, s
 		return false
 	}
 
-	cacheCleanupOnce.Do(startCacheCleanup)
+	startCacheCleanup()
 	now := time.Now()
 	codexReasoningReplayMu.Lock()
 	entry := codexReasoningReplayEntries[key]
@@ -277,7 +277,7 @@ func GetCodexReasoningReplayItemsRequired(ctx context.Context, modelName, sessio
 		return cloneCodexReasoningReplayItems(homeItems), true, nil
 	}
 
-	cacheCleanupOnce.Do(startCacheCleanup)
+	startCacheCleanup()
 	now := time.Now()
 	codexReasoningReplayMu.Lock()
 	defer codexReasoningReplayMu.Unlock()
@@ -37,8 +37,17 @@ const (
 // signatureCache stores signatures by model group -> textHash -> SignatureEntry
 var signatureCache sync.Map
 
-// cacheCleanupOnce ensures the background cleanup goroutine starts only once
-var cacheCleanupOnce sync.Once
+// cacheCleanupMu guards the lifecycle of the background cleanup goroutine.
+var cacheCleanupMu sync.Mutex
+
+// cacheCleanupRunning is the lock-free fast path for startCacheCleanup.
+var cacheCleanupRunning atomic.Bool
+
+// cacheCleanupStop and cacheCleanupDone signal and confirm cleanup goroutine shutdown.
+var (
+	cacheCleanupStop chan struct{}
+	cacheCleanupDone chan struct{}
+)
 
 type signatureKVClient interface {
 	KVGet(ctx context.Context, key string) ([]byte, bool, error)
@@ -66,7 +75,7 @@ func hashText(text string) string {
 // getOrCreateGroupCache gets or creates a cache bucket for a model group
 func getOrCreateGroupCache(groupKey string) *groupCache {
 	// Start background cleanup on first access
-	cacheCleanupOnce.Do(startCacheCleanup)
+	startCacheCleanup()
 
 	if val, ok := signatureCache.Load(groupKey); ok {
 		return val.(*groupCache)
@@ -77,17 +86,51 @@ func getOrCreateGroupCache(groupKey string) *groupCache {
 }
 
 // startCacheCleanup launches a background goroutine that periodically
-// removes caches where all entries have expired.
+// removes caches where all entries have expired. It is a no-op while the
+// goroutine is running, and starts a new one after StopCacheCleanup.
 func startCacheCleanup() {
+	if cacheCleanupRunning.Load() {
+		return
+	}
+	cacheCleanupMu.Lock()
+	defer cacheCleanupMu.Unlock()
+	if cacheCleanupRunning.Load() {
+		return
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	cacheCleanupStop, cacheCleanupDone = stop, done
+	cacheCleanupRunning.Store(true)
 	go func() {
+		defer close(done)
 		ticker := time.NewTicker(CacheCleanupInterval)
 		defer ticker.Stop()
-		for range ticker.C {
-			purgeExpiredCaches()
+		for {
+			select {
+			case <-ticker.C:
+				purgeExpiredCaches()
+			case <-stop:
+				return
+			}
 		}
 	}()
 }
 
+// StopCacheCleanup stops the background cleanup goroutine and waits for it to exit.
+// The goroutine is started again lazily on the next cache access.
+func StopCacheCleanup() {
+	cacheCleanupMu.Lock()
+	defer cacheCleanupMu.Unlock()
+	if !cacheCleanupRunning.Load() {
+		return
+	}
+	close(cacheCleanupStop)
+	<-cacheCleanupDone
+	cacheCleanupStop, cacheCleanupDone = nil, nil
+	cacheCleanupRunning.Store(false)
+}
+
 // purgeExpiredCaches removes caches with no valid (non-expired) entries.
 func purgeExpiredCaches() {
 	now := time.Now()
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -391,13 +392,28 @@ func TestCacheSignature_Overwrite(t *testing.T) {
 	}
 }
 
-// Note: TTL expiration test is tricky to test without mocking time
-// We test the logic path exists but actual expiration would require time manipulation
+// setSignatureEntryTimestamp ages a cached signature entry so TTL handling can be tested without waiting.
+func setSignatureEntryTimestamp(t *testing.T, modelName, text string, ts time.Time) {
+	t.Helper()
+	val, ok := signatureCache.Load(GetModelGroup(modelName))
+	if !ok {
+		t.Fatalf("no signature cache bucket for %s", modelName)
+	}
+	sc := val.(*groupCache)
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	textHash := hashText(text)
+	entry, exists := sc.entries[textHash]
+	if !exists {
+		t.Fatalf("no cached signature for %q", text)
+	}
+	entry.Timestamp = ts
+	sc.entries[textHash] = entry
+}
+
 func TestCacheSignature_ExpirationLogic(t *testing.T) {
 	ClearSignatureCache("")
 
-	// This test verifies the expiration check exists
-	// In a real scenario, we'd mock time.Now()
 	text := "text"
 	sig := "validSig1234567890123456789012345678901234567890123456"
 
@@ -408,9 +424,83 @@ func TestCacheSignature_ExpirationLogic(t *testing.T) {
 		t.Errorf("Fresh entry should be retrievable, got '%s'", got)
 	}
 
-	// We can't easily test actual expiration without time mocking
-	// but the logic is verified by the implementation
-	_ = time.Now() // Acknowledge we're not testing time passage
+	setSignatureEntryTimestamp(t, testModelName, text, time.Now().Add(-SignatureCacheTTL-time.Minute))
+	if got := GetCachedSignature(testModelName, text); got != "" {
+		t.Errorf("Expired entry should not be returned, got '%s'", got)
+	}
+}
+
+func TestPurgeExpiredCaches_RemovesExpiredEntries(t *testing.T) {
+	ClearSignatureCache("")
+
+	sig := "validSig1234567890123456789012345678901234567890123456"
+	CacheSignature(testModelName, "stale", sig)
+	CacheSignature("gemini-2.5-pro", "stale", sig)
+	CacheSignature("gemini-2.5-pro", "fresh", sig)
+
+	aged := time.Now().Add(-SignatureCacheTTL - time.Minute)
+	setSignatureEntryTimestamp(t, testModelName, "stale", aged)
+	setSignatureEntryTimestamp(t, "gemini-2.5-pro", "stale", aged)
+
+	purgeExpiredCaches()
+
+	if _, ok := signatureCache.Load(GetModelGroup(testModelName)); ok {
+		t.Error("bucket with only expired entries should be deleted")
+	}
+	val, ok := signatureCache.Load("gemini")
+	if !ok {
+		t.Fatal("bucket with a fresh entry should be kept")
+	}
+	sc := val.(*groupCache)
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	if _, exists := sc.entries[hashText("stale")]; exists {
+		t.Error("expired entry should be purged")
+	}
+	if _, exists := sc.entries[hashText("fresh")]; !exists {
+		t.Error("fresh entry should survive purge")
+	}
+}
+
+func TestCacheCleanup_StopAndRestart(t *testing.T) {
+	StopCacheCleanup()
+	t.Cleanup(StopCacheCleanup)
+	baseline := runtime.NumGoroutine()
+
+	startCacheCleanup()
+	startCacheCleanup()
+	if !cacheCleanupRunning.Load() {
+		t.Fatal("cleanup should be running after start")
+	}
+	// Unrelated goroutines may exit concurrently, so only a lower bound is stable here.
+	if got := runtime.NumGoroutine(); got < baseline+1 {
+		t.Fatalf("goroutines after double start = %d, want at least %d", got, baseline+1)
+	}
+
+	StopCacheCleanup()
+	StopCacheCleanup()
+	if cacheCleanupRunning.Load() {
+		t.Fatal("cleanup should not be running after stop")
+	}
+	waitForGoroutineCount(t, baseline)
+
+	CacheSignature(testModelName, "restart", "validSig1234567890123456789012345678901234567890123456")
+	if !cacheCleanupRunning.Load() {
+		t.Fatal("cache access should restart cleanup after stop")
+	}
+	StopCacheCleanup()
+	waitForGoroutineCount(t, baseline)
+}
+
+func waitForGoroutineCount(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines = %d, want %d", runtime.NumGoroutine(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
 }
 
 func TestSignatureModeSetters_LogAtInfoLevel(t *testing.T) {
@@ -117,7 +117,7 @@ func StoreXAIReasoningReplayItems(ctx context.Context, modelName, sessionKey str
 		return XAIReasoningReplayStored
 	}
 
-	cacheCleanupOnce.Do(startCacheCleanup)
+	startCacheCleanup()
 	now := time.Now()
 	xaiReasoningReplayMu.Lock()
 	defer xaiReasoningReplayMu.Unlock()
@@ -174,7 +174,7 @@ func GetXAIReasoningReplayItemsRequired(ctx context.Context, modelName, sessionK
 		return cloneXAIReasoningReplayItems(homeItems), true, nil
 	}
 
-	cacheCleanupOnce.Do(startCacheCleanup)
+	startCacheCleanup()
 	now := time.Now()
 	xaiReasoningReplayMu.Lock()
 	defer xaiReasoningReplayMu.Unlock()
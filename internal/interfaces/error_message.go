@@ -3,7 +3,10 @@
 // such as AI service clients, API handlers, and data models.
 package interfaces
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 // ErrorMessage encapsulates an error with an associated HTTP status code.
 // This structure is used to provide detailed error information including
@@ -17,4 +20,10 @@ type ErrorMessage struct {
 
 	// Addon contains additional headers to be added to the response.
 	Addon http.Header
+
+	// RetryAfter is the upstream retry hint, zero when the upstream gave none.
+	RetryAfter time.Duration
+
+	// Provider identifies the upstream provider that produced the error, if known.
+	Provider string
 }
@@ -5,6 +5,7 @@ import (
 	"compress/gzip"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -2979,3 +2980,40 @@ func TestEnsureClaudeThinkingDisplay_SkipsWhenThinkingMissing(t *testing.T) {
 		t.Fatalf("thinking should remain absent: %s", out)
 	}
 }
+
+func TestClaudeExecutor_Execute_RateLimitErrorCarriesRetryAfterAndProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "17")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"rate_limit_error","message":"rate limited"}}`))
+	}))
+	defer server.Close()
+
+	executor := NewClaudeExecutor(&config.Config{})
+	auth := &cliproxyauth.Auth{Attributes: map[string]string{
+		"api_key":  "key-123",
+		"base_url": server.URL,
+	}}
+	payload := []byte(`{"messages":[{"role":"user","content":[{"type":"text","text":"hi"}]}]}`)
+
+	_, err := executor.Execute(context.Background(), auth, cliproxyexecutor.Request{
+		Model:   "claude-3-5-sonnet-20241022",
+		Payload: payload,
+	}, cliproxyexecutor.Options{
+		SourceFormat: sdktranslator.FromString("claude"),
+	})
+	var sErr statusErr
+	if !errors.As(err, &sErr) {
+		t.Fatalf("expected statusErr, got %T: %v", err, err)
+	}
+	if sErr.StatusCode() != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", sErr.StatusCode(), http.StatusTooManyRequests)
+	}
+	if retryAfter := sErr.RetryAfter(); retryAfter == nil || *retryAfter != 17*time.Second {
+		t.Fatalf("retryAfter = %v, want 17s", retryAfter)
+	}
+	if got := sErr.Provider(); got != "claude" {
+		t.Fatalf("provider = %q, want claude", got)
+	}
+}
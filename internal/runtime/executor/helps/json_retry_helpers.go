@@ -2,6 +2,7 @@ package helps
 
 import (
 	"fmt"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
@@ -23,6 +24,36 @@ func DeleteJSONField(body []byte, key string) []byte {
 	return updated
 }
 
+// ParseRetryAfterHeader extracts a retry hint from the Retry-After response header,
+// read as delta-seconds or an HTTP-date. Anthropic's anthropic-ratelimit-*-reset headers
+// are deliberately ignored: they report when each limit is fully refilled, not when the
+// limit that was hit allows another request, and would overstate the cooldown.
+// Returns nil when no hint is present or the hint is not in the future (e.g. a zero
+// value or clock skew), leaving the caller's default backoff in effect.
+func ParseRetryAfterHeader(header http.Header, now time.Time) *time.Duration {
+	if header == nil {
+		return nil
+	}
+	raw := strings.TrimSpace(header.Get("Retry-After"))
+	if raw == "" {
+		return nil
+	}
+	if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return positiveDuration(time.Duration(seconds) * time.Second)
+	}
+	if when, err := http.ParseTime(raw); err == nil {
+		return positiveDuration(when.Sub(now))
+	}
+	return nil
+}
+
+func positiveDuration(d time.Duration) *time.Duration {
+	if d <= 0 {
+		return nil
+	}
+	return &d
+}
+
 // ParseRetryDelay extracts the retry delay from a Google API 429 error response.
 func ParseRetryDelay(errorBody []byte) (*time.Duration, error) {
 	details := gjson.GetBytes(errorBody, "error.details")
@@ -0,0 +1,71 @@
+package helps
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfterHeader(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   *time.Duration
+	}{
+		{
+			name:   "seconds",
+			header: http.Header{"Retry-After": {"30"}},
+			want:   durationPtr(30 * time.Second),
+		},
+		{
+			name:   "http date",
+			header: http.Header{"Retry-After": {now.Add(90 * time.Second).Format(http.TimeFormat)}},
+			want:   durationPtr(90 * time.Second),
+		},
+		{
+			name:   "http date in the past",
+			header: http.Header{"Retry-After": {now.Add(-time.Minute).Format(http.TimeFormat)}},
+			want:   nil,
+		},
+		{
+			name:   "zero seconds",
+			header: http.Header{"Retry-After": {"0"}},
+			want:   nil,
+		},
+		{
+			name:   "anthropic reset headers ignored",
+			header: http.Header{"Anthropic-Ratelimit-Tokens-Reset": {now.Add(45 * time.Second).Format(time.RFC3339)}},
+			want:   nil,
+		},
+		{
+			name:   "invalid",
+			header: http.Header{"Retry-After": {"soon"}},
+			want:   nil,
+		},
+		{
+			name:   "missing",
+			header: http.Header{},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseRetryAfterHeader(tt.header, now)
+			switch {
+			case tt.want == nil && got != nil:
+				t.Fatalf("ParseRetryAfterHeader() = %v, want nil", *got)
+			case tt.want != nil && got == nil:
+				t.Fatalf("ParseRetryAfterHeader() = nil, want %v", *tt.want)
+			case tt.want != nil && *got != *tt.want:
+				t.Fatalf("ParseRetryAfterHeader() = %v, want %v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
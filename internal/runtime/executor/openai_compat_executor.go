@@ -786,6 +786,7 @@ type statusErr struct {
 	code       int
 	msg        string
 	retryAfter *time.Duration
+	provider   string
 }
 
 func (e statusErr) Error() string {
@@ -796,3 +797,4 @@ func (e statusErr) Error() string {
 }
 func (e statusErr) StatusCode() int            { return e.code }
 func (e statusErr) RetryAfter() *time.Duration { return e.retryAfter }
+func (e statusErr) Provider() string           { return e.provider }
@@ -7,44 +7,47 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/constant"
 	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
 	"github.com/tidwall/gjson"
 )
 
 func TestClaudeErrorExtractsOpenAIStyleUpstreamJSON(t *testing.T) {
-	handler := &ClaudeCodeAPIHandler{}
 	msg := &interfaces.ErrorMessage{
 		StatusCode: http.StatusBadRequest,
 		Error:      errors.New(`{"error":{"message":"Your input exceeds the context window of this model. Please adjust your input and try again.","type":"invalid_request_error","code":"context_too_large"}}`),
 	}
 
-	got := handler.toClaudeError(msg)
+	got := gjson.ParseBytes(handlers.RenderErrorMessage(constant.Claude, msg))
 
-	if got.Type != "error" {
-		t.Fatalf("type = %q, want error", got.Type)
+	if got.Get("type").String() != "error" {
+		t.Fatalf("type = %q, want error", got.Get("type").String())
 	}
-	if got.Error.Type != "invalid_request_error" {
-		t.Fatalf("error.type = %q, want invalid_request_error", got.Error.Type)
+	if got.Get("error.type").String() != "invalid_request_error" {
+		t.Fatalf("error.type = %q, want invalid_request_error", got.Get("error.type").String())
 	}
-	if got.Error.Message != "Your input exceeds the context window of this model. Please adjust your input and try again." {
-		t.Fatalf("error.message = %q", got.Error.Message)
+	if got.Get("error.message").String() != "Your input exceeds the context window of this model. Please adjust your input and try again." {
+		t.Fatalf("error.message = %q", got.Get("error.message").String())
 	}
 }
 
 func TestClaudeErrorExtractsClaudeStyleUpstreamJSON(t *testing.T) {
-	handler := &ClaudeCodeAPIHandler{}
 	msg := &interfaces.ErrorMessage{
 		StatusCode: http.StatusTooManyRequests,
 		Error:      errors.New(`{"type":"error","error":{"type":"rate_limit_error","message":"This request would exceed your account's rate limit. Please try again later."},"request_id":"req_123"}`),
 	}
 
-	got := handler.toClaudeError(msg)
+	got := gjson.ParseBytes(handlers.RenderErrorMessage(constant.Claude, msg))
 
-	if got.Error.Type != "rate_limit_error" {
-		t.Fatalf("error.type = %q, want rate_limit_error", got.Error.Type)
+	if got.Get("error.type").String() != "rate_limit_error" {
+		t.Fatalf("error.type = %q, want rate_limit_error", got.Get("error.type").String())
+	}
+	if got.Get("error.message").String() != "This request would exceed your account's rate limit. Please try again later." {
+		t.Fatalf("error.message = %q", got.Get("error.message").String())
 	}
-	if got.Error.Message != "This request would exceed your account's rate limit. Please try again later." {
-		t.Fatalf("error.message = %q", got.Error.Message)
+	if got.Get("request_id").String() != "req_123" {
+		t.Fatalf("request_id = %q, want req_123", got.Get("request_id").String())
 	}
 }
 
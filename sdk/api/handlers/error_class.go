@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+	"github.com/tidwall/gjson"
+)
+
+// ErrorClass is a coarse, provider-agnostic category for an upstream error.
+type ErrorClass int
+
+const (
+	// ErrorClassUnknown is returned when the error cannot be categorized.
+	ErrorClassUnknown ErrorClass = iota
+	// ErrorClassRateLimited marks quota or rate limit failures (HTTP 429 and equivalents).
+	ErrorClassRateLimited
+	// ErrorClassServerError marks upstream 5xx failures.
+	ErrorClassServerError
+	// ErrorClassInvalidThinkingSignature marks rejected thinking block signatures.
+	ErrorClassInvalidThinkingSignature
+	// ErrorClassClientError marks any other 4xx failure.
+	ErrorClassClientError
+)
+
+// String returns a stable lowercase name for the class.
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassRateLimited:
+		return "rate_limited"
+	case ErrorClassServerError:
+		return "server_error"
+	case ErrorClassInvalidThinkingSignature:
+		return "invalid_thinking_signature"
+	case ErrorClassClientError:
+		return "client_error"
+	default:
+		return "unknown"
+	}
+}
+
+// Classify derives an ErrorClass from the status code and error body of msg.
+func Classify(msg *interfaces.ErrorMessage) ErrorClass {
+	if msg == nil {
+		return ErrorClassUnknown
+	}
+	errText := ""
+	if msg.Error != nil {
+		errText = strings.TrimSpace(msg.Error.Error())
+	}
+	status := msg.StatusCode
+
+	if status == http.StatusTooManyRequests || isRateLimitErrorText(errText) {
+		return ErrorClassRateLimited
+	}
+	if status >= http.StatusInternalServerError {
+		return ErrorClassServerError
+	}
+	if (status == 0 || status >= http.StatusBadRequest) && isThinkingSignatureErrorText(errText) {
+		return ErrorClassInvalidThinkingSignature
+	}
+	if status >= http.StatusBadRequest {
+		return ErrorClassClientError
+	}
+	return ErrorClassUnknown
+}
+
+// thinkingSignatureErrorPhrase is the exact upstream wording required in strict match mode.
+const thinkingSignatureErrorPhrase = "invalid signature in thinking block"
+
+// thinkingSignatureErrorCodes identify a rejected thinking signature independently of the
+// message wording, e.g. the code the Codex executor assigns to normalized signature errors.
+var thinkingSignatureErrorCodes = map[string]struct{}{
+	"thinking_signature_invalid": {},
+	"invalid_encrypted_content":  {},
+}
+
+// thinkingModifiedErrorPhrases identify the sibling rejection of reordered or edited thinking
+// blocks, which is resolved the same way as an invalid signature.
+var thinkingModifiedErrorPhrases = []string{"cannot be modified", "must not be modified"}
+
+var thinkingSignatureStrictMatch atomic.Bool
+
+// SetThinkingSignatureStrictMatch switches thinking signature error detection between the
+// default keyword match and an exact, case-insensitive phrase match.
+func SetThinkingSignatureStrictMatch(strict bool) {
+	thinkingSignatureStrictMatch.Store(strict)
+}
+
+// ThinkingSignatureStrictMatch reports whether exact phrase matching is enabled.
+func ThinkingSignatureStrictMatch() bool {
+	return thinkingSignatureStrictMatch.Load()
+}
+
+// defaultThinkingSignatureKeywordGroups is the keyword match used outside strict mode.
+var defaultThinkingSignatureKeywordGroups = [][]string{{"invalid"}, {"signature"}, {"thinking"}}
+
+var thinkingSignatureKeywordGroups atomic.Pointer[[][]string]
+
+// SetThinkingSignatureKeywordGroups replaces the keyword groups used by the default
+// (non-strict) thinking signature match. A message matches when it contains at least one
+// synonym from every group, e.g. {{"invalid"}, {"signature"}, {"thinking", "reasoning"}}.
+// Keywords are matched case-insensitively; empty groups are dropped, and an empty set
+// restores the default groups.
+func SetThinkingSignatureKeywordGroups(groups [][]string) {
+	normalized := make([][]string, 0, len(groups))
+	for _, group := range groups {
+		synonyms := make([]string, 0, len(group))
+		for _, keyword := range group {
+			if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+				synonyms = append(synonyms, keyword)
+			}
+		}
+		if len(synonyms) > 0 {
+			normalized = append(normalized, synonyms)
+		}
+	}
+	if len(normalized) == 0 {
+		thinkingSignatureKeywordGroups.Store(nil)
+		return
+	}
+	thinkingSignatureKeywordGroups.Store(&normalized)
+}
+
+// ThinkingSignatureKeywordGroups returns a copy of the active keyword groups.
+func ThinkingSignatureKeywordGroups() [][]string {
+	groups := activeThinkingSignatureKeywordGroups()
+	out := make([][]string, len(groups))
+	for i, group := range groups {
+		out[i] = append([]string(nil), group...)
+	}
+	return out
+}
+
+func activeThinkingSignatureKeywordGroups() [][]string {
+	if groups := thinkingSignatureKeywordGroups.Load(); groups != nil {
+		return *groups
+	}
+	return defaultThinkingSignatureKeywordGroups
+}
+
+// extractErrorMessage returns the human-readable message of a JSON error body,
+// falling back to the raw text when no message field is present. For a top-level
+// array of errors, the first element describing a thinking signature error wins,
+// otherwise the first element carrying a message.
+func extractErrorMessage(errText string) string {
+	trimmed := strings.TrimSpace(errText)
+	if trimmed == "" || !gjson.Valid(trimmed) {
+		return trimmed
+	}
+	root := gjson.Parse(trimmed)
+	if root.IsArray() {
+		items := root.Array()
+		for _, item := range items {
+			if message := errorMessageField(item); message != "" && isThinkingSignatureErrorText(item.Raw) {
+				return message
+			}
+		}
+		for _, item := range items {
+			if message := errorMessageField(item); message != "" {
+				return message
+			}
+		}
+		return trimmed
+	}
+	if message := errorMessageField(root); message != "" {
+		return message
+	}
+	return trimmed
+}
+
+// errorMessageField returns error.message or message from a parsed error object.
+func errorMessageField(root gjson.Result) string {
+	for _, path := range []string{"error.message", "message"} {
+		if message := strings.TrimSpace(root.Get(path).String()); message != "" {
+			return message
+		}
+	}
+	return ""
+}
+
+// isThinkingSignatureErrorText reports whether errText describes an invalid thinking block signature
+// or a thinking block that was modified. A structured error code is checked first; matching on
+// the message is the fallback. Each element of a top-level error array is checked in turn.
+func isThinkingSignatureErrorText(errText string) bool {
+	if trimmed := strings.TrimSpace(errText); strings.HasPrefix(trimmed, "[") && gjson.Valid(trimmed) {
+		for _, item := range gjson.Parse(trimmed).Array() {
+			if isThinkingSignatureErrorText(item.Raw) {
+				return true
+			}
+		}
+		return false
+	}
+	if code := errorCode(errText); code != "" {
+		if _, ok := thinkingSignatureErrorCodes[code]; ok {
+			return true
+		}
+	}
+	lower := strings.ToLower(extractErrorMessage(errText))
+	if isThinkingModifiedErrorMessage(lower) {
+		return true
+	}
+	if ThinkingSignatureStrictMatch() {
+		return strings.Contains(lower, thinkingSignatureErrorPhrase)
+	}
+	return containsThinkingSignatureKeywords(lower)
+}
+
+// containsThinkingSignatureKeywords reports whether a lowercase message contains a synonym
+// from every active keyword group.
+func containsThinkingSignatureKeywords(lower string) bool {
+	for _, group := range activeThinkingSignatureKeywordGroups() {
+		matched := false
+		for _, keyword := range group {
+			if strings.Contains(lower, keyword) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// isThinkingModifiedErrorMessage reports whether a lowercase message rejects modified thinking
+// blocks, e.g. "`thinking` or `redacted_thinking` blocks in the latest assistant message cannot
+// be modified". The phrase must follow "thinking" and then "block", so unrelated settings such
+// as a thinking budget that cannot be modified do not match.
+func isThinkingModifiedErrorMessage(lower string) bool {
+	for _, phrase := range thinkingModifiedErrorPhrases {
+		idx := strings.Index(lower, phrase)
+		if idx < 0 {
+			continue
+		}
+		prefix := lower[:idx]
+		if thinking := strings.LastIndex(prefix, "thinking"); thinking >= 0 && strings.Contains(prefix[thinking:], "block") {
+			return true
+		}
+	}
+	return false
+}
+
+// errorCode returns the lowercase string error code of a JSON error body, if any.
+func errorCode(errText string) string {
+	trimmed := strings.TrimSpace(errText)
+	if trimmed == "" || !gjson.Valid(trimmed) {
+		return ""
+	}
+	code := gjson.Get(trimmed, "error.code")
+	if code.Type != gjson.String {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(code.String()))
+}
+
+func isRateLimitErrorText(errText string) bool {
+	trimmed := strings.TrimSpace(errText)
+	if trimmed == "" || !gjson.Valid(trimmed) {
+		return false
+	}
+	root := gjson.Parse(trimmed)
+	return root.Get("error.type").String() == "rate_limit_error" ||
+		root.Get("error.status").String() == "RESOURCE_EXHAUSTED"
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *interfaces.ErrorMessage
+		want ErrorClass
+	}{
+		{
+			name: "nil",
+			msg:  nil,
+			want: ErrorClassUnknown,
+		},
+		{
+			name: "rate limited status",
+			msg:  &interfaces.ErrorMessage{StatusCode: http.StatusTooManyRequests, Error: errors.New("slow down")},
+			want: ErrorClassRateLimited,
+		},
+		{
+			name: "rate limited body",
+			msg:  &interfaces.ErrorMessage{StatusCode: http.StatusBadRequest, Error: errors.New(`{"error":{"code":429,"status":"RESOURCE_EXHAUSTED","message":"quota"}}`)},
+			want: ErrorClassRateLimited,
+		},
+		{
+			name: "server error",
+			msg:  &interfaces.ErrorMessage{StatusCode: http.StatusServiceUnavailable, Error: errors.New("overloaded")},
+			want: ErrorClassServerError,
+		},
+		{
+			name: "invalid thinking signature json",
+			msg:  &interfaces.ErrorMessage{StatusCode: http.StatusBadRequest, Error: errors.New(`{"type":"error","error":{"type":"invalid_request_error","message":"messages.1.content.0: Invalid signature in thinking block"}}`)},
+			want: ErrorClassInvalidThinkingSignature,
+		},
+		{
+			name: "invalid thinking signature plain",
+			msg:  &interfaces.ErrorMessage{Error: errors.New("Invalid signature in thinking block")},
+			want: ErrorClassInvalidThinkingSignature,
+		},
+		{
+			name: "client error",
+			msg:  &interfaces.ErrorMessage{StatusCode: http.StatusBadRequest, Error: errors.New(`{"error":{"message":"max_tokens is required"}}`)},
+			want: ErrorClassClientError,
+		},
+		{
+			name: "unknown",
+			msg:  &interfaces.ErrorMessage{Error: errors.New("connection reset")},
+			want: ErrorClassUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.msg); got != tt.want {
+				t.Fatalf("Classify() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsThinkingSignatureErrorText_StrictMatch(t *testing.T) {
+	t.Cleanup(func() { SetThinkingSignatureStrictMatch(false) })
+
+	exact := `{"error":{"message":"messages.1.content.0: Invalid Signature In Thinking Block"}}`
+	loose := `{"error":{"message":"Invalid request. The signature header is missing. Extended thinking is enabled."}}`
+
+	if !isThinkingSignatureErrorText(exact) || !isThinkingSignatureErrorText(loose) {
+		t.Fatal("default mode should match both exact and keyword-only messages")
+	}
+
+	SetThinkingSignatureStrictMatch(true)
+	if !isThinkingSignatureErrorText(exact) {
+		t.Fatal("strict mode should match the exact phrase case-insensitively")
+	}
+	if isThinkingSignatureErrorText(loose) {
+		t.Fatal("strict mode should reject keywords spread across unrelated sentences")
+	}
+}
+
+func TestIsThinkingSignatureErrorText_StructuredCode(t *testing.T) {
+	t.Cleanup(func() { SetThinkingSignatureStrictMatch(false) })
+
+	localized := `{"error":{"type":"invalid_request_error","code":"thinking_signature_invalid","message":"思考ブロックの署名が無効です"}}`
+	if !isThinkingSignatureErrorText(localized) {
+		t.Fatal("structured error code should match a fully translated message")
+	}
+
+	SetThinkingSignatureStrictMatch(true)
+	if !isThinkingSignatureErrorText(localized) {
+		t.Fatal("structured error code should match in strict mode")
+	}
+
+	SetThinkingSignatureStrictMatch(false)
+	otherCode := `{"error":{"type":"invalid_request_error","code":"context_too_large","message":"思考ブロックの署名が無効です"}}`
+	if isThinkingSignatureErrorText(otherCode) {
+		t.Fatal("unrelated error code with a translated message should not match")
+	}
+}
+
+func TestIsThinkingSignatureErrorText_ModifiedThinkingBlocks(t *testing.T) {
+	t.Cleanup(func() { SetThinkingSignatureStrictMatch(false) })
+
+	messages := []string{
+		`{"type":"error","error":{"type":"invalid_request_error","message":"messages.3.content.1: ` + "`thinking` or `redacted_thinking`" + ` blocks in the latest assistant message cannot be modified. These blocks must remain as they were in the original response."}}`,
+		"thinking blocks in the assistant turn must not be modified",
+	}
+	for _, strict := range []bool{false, true} {
+		SetThinkingSignatureStrictMatch(strict)
+		for _, message := range messages {
+			if !isThinkingSignatureErrorText(message) {
+				t.Fatalf("strict=%v: expected match for %q", strict, message)
+			}
+		}
+	}
+
+	for _, strict := range []bool{false, true} {
+		SetThinkingSignatureStrictMatch(strict)
+		for _, message := range []string{
+			"system prompt cannot be modified",
+			"thinking budget cannot be modified for this model",
+			`{"error":{"message":"thinking.type must not be modified mid-conversation"}}`,
+		} {
+			if isThinkingSignatureErrorText(message) {
+				t.Fatalf("strict=%v: unexpected match for %q", strict, message)
+			}
+		}
+	}
+
+	SetThinkingSignatureStrictMatch(false)
+	got := Classify(&interfaces.ErrorMessage{StatusCode: http.StatusBadRequest, Error: errors.New(messages[0])})
+	if got != ErrorClassInvalidThinkingSignature {
+		t.Fatalf("Classify() = %s, want %s", got, ErrorClassInvalidThinkingSignature)
+	}
+}
+
+func TestIsThinkingSignatureErrorText_TopLevelArray(t *testing.T) {
+	single := `[{"error":{"message":"messages.1.content.0: Invalid signature in thinking block"}}]`
+	if !isThinkingSignatureErrorText(single) {
+		t.Fatal("single-element array should match")
+	}
+	if got := extractErrorMessage(single); got != "messages.1.content.0: Invalid signature in thinking block" {
+		t.Fatalf("extractErrorMessage() = %q", got)
+	}
+
+	multi := `[{"error":{"message":"upstream overloaded"}},{"message":"Invalid signature in thinking block"}]`
+	if !isThinkingSignatureErrorText(multi) {
+		t.Fatal("a later array element carrying the phrase should match")
+	}
+	if got := extractErrorMessage(multi); got != "Invalid signature in thinking block" {
+		t.Fatalf("extractErrorMessage() = %q, want the signature element's message", got)
+	}
+
+	unrelated := `[{"error":{"message":"upstream overloaded"}},{"error":{"message":"try again"}}]`
+	if isThinkingSignatureErrorText(unrelated) {
+		t.Fatal("array without the phrase should not match")
+	}
+	if got := extractErrorMessage(unrelated); got != "upstream overloaded" {
+		t.Fatalf("extractErrorMessage() = %q, want first message", got)
+	}
+	got := Classify(&interfaces.ErrorMessage{StatusCode: http.StatusBadRequest, Error: errors.New(multi)})
+	if got != ErrorClassInvalidThinkingSignature {
+		t.Fatalf("Classify() = %s, want %s", got, ErrorClassInvalidThinkingSignature)
+	}
+}
+
+func TestIsThinkingSignatureErrorText_KeywordGroups(t *testing.T) {
+	t.Cleanup(func() { SetThinkingSignatureKeywordGroups(nil) })
+
+	reasoning := `{"error":{"message":"Invalid signature for reasoning block"}}`
+	if isThinkingSignatureErrorText(reasoning) {
+		t.Fatal("default keyword groups should not match a reasoning block error")
+	}
+
+	SetThinkingSignatureKeywordGroups([][]string{{"Invalid"}, {"signature"}, {"thinking", "reasoning", " "}, {}})
+	if !isThinkingSignatureErrorText(reasoning) {
+		t.Fatal("registered reasoning synonym should match")
+	}
+	if !isThinkingSignatureErrorText("Invalid signature in thinking block") {
+		t.Fatal("original thinking keyword should still match")
+	}
+	if isThinkingSignatureErrorText("invalid reasoning effort") {
+		t.Fatal("a message missing the signature group should not match")
+	}
+	if got := ThinkingSignatureKeywordGroups(); len(got) != 3 || len(got[2]) != 2 || got[0][0] != "invalid" {
+		t.Fatalf("ThinkingSignatureKeywordGroups() = %q", got)
+	}
+
+	SetThinkingSignatureKeywordGroups(nil)
+	if isThinkingSignatureErrorText(reasoning) {
+		t.Fatal("resetting should restore the default keyword groups")
+	}
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/constant"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+	"github.com/tidwall/gjson"
+)
+
+type claudeErrorResponse struct {
+	Type  string            `json:"type"`
+	Error claudeErrorDetail `json:"error"`
+}
+
+type claudeErrorDetail struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type geminiErrorBody struct {
+	Error geminiErrorDetail `json:"error"`
+}
+
+type geminiErrorDetail struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+// errorMessageStatusText returns the effective HTTP status and error text of msg,
+// defaulting to 500 and the status text when either is missing.
+func errorMessageStatusText(msg *interfaces.ErrorMessage) (int, string) {
+	status := http.StatusInternalServerError
+	if msg != nil && msg.StatusCode > 0 {
+		status = msg.StatusCode
+	}
+	errText := http.StatusText(status)
+	if msg != nil && msg.Error != nil {
+		if v := strings.TrimSpace(msg.Error.Error()); v != "" {
+			errText = v
+		}
+	}
+	return status, errText
+}
+
+// RenderErrorMessage renders msg as a non-stream error body in the given handler format:
+// an Anthropic error for constant.Claude, a Google RPC error for constant.Gemini and an
+// OpenAI error object otherwise. The status code of msg, defaulting to 500, is embedded
+// wherever the target format carries one.
+func RenderErrorMessage(format string, msg *interfaces.ErrorMessage) []byte {
+	status, errText := errorMessageStatusText(msg)
+	switch format {
+	case constant.Claude:
+		return buildClaudeErrorBody(status, errText)
+	case constant.Gemini:
+		return buildGeminiErrorBody(status, errText)
+	default:
+		return buildOpenAIErrorBody(status, errText)
+	}
+}
+
+// buildOpenAIErrorBody returns errText unchanged when it already carries error.message, which
+// covers OpenAI-compatible and Gemini error bodies. An Anthropic envelope has its message
+// re-wrapped in an OpenAI error object, and any other text, including JSON without a message,
+// becomes the message of an OpenAI error as-is.
+func buildOpenAIErrorBody(status int, errText string) []byte {
+	trimmed := strings.TrimSpace(errText)
+	if gjson.Valid(trimmed) {
+		root := gjson.Parse(trimmed)
+		if root.Get("type").String() == "error" {
+			return newOpenAIErrorResponseBody(status, extractErrorMessage(trimmed))
+		}
+		if root.Get("error.message").Exists() {
+			return []byte(trimmed)
+		}
+	}
+	return newOpenAIErrorResponseBody(status, trimmed)
+}
+
+// claudeErrorTypeFromStatus maps an HTTP status to the matching Anthropic error type.
+func claudeErrorTypeFromStatus(status int) string {
+	switch status {
+	case http.StatusUnauthorized:
+		return "authentication_error"
+	case http.StatusPaymentRequired:
+		return "billing_error"
+	case http.StatusForbidden:
+		return "permission_error"
+	case http.StatusNotFound:
+		return "not_found_error"
+	case http.StatusRequestEntityTooLarge:
+		return "request_too_large"
+	case http.StatusTooManyRequests:
+		return "rate_limit_error"
+	case http.StatusGatewayTimeout:
+		return "timeout_error"
+	case 529:
+		return "overloaded_error"
+	default:
+		if status >= http.StatusInternalServerError {
+			return "api_error"
+		}
+		return "invalid_request_error"
+	}
+}
+
+func newClaudeErrorResponse(status int, errText string) claudeErrorResponse {
+	errType, message := claudeErrorDetailFromText(status, errText)
+	return claudeErrorResponse{
+		Type: "error",
+		Error: claudeErrorDetail{
+			Type:    errType,
+			Message: message,
+		},
+	}
+}
+
+// claudeErrorDetailFromText derives the Anthropic error type and message from an upstream
+// error text. JSON bodies in Anthropic or OpenAI shape keep their own type and message;
+// anything else is typed by status.
+func claudeErrorDetailFromText(status int, errText string) (string, string) {
+	message := strings.TrimSpace(errText)
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	errType := claudeErrorTypeFromStatus(status)
+
+	var payload map[string]any
+	if json.Valid([]byte(message)) {
+		if err := json.Unmarshal([]byte(message), &payload); err == nil {
+			if e, ok := payload["error"].(map[string]any); ok {
+				if t, ok := e["type"].(string); ok && strings.TrimSpace(t) != "" {
+					errType = strings.TrimSpace(t)
+				}
+				if m, ok := e["message"].(string); ok && strings.TrimSpace(m) != "" {
+					message = strings.TrimSpace(m)
+				} else if c, ok := e["code"].(string); ok && strings.TrimSpace(c) != "" {
+					message = strings.TrimSpace(c)
+				}
+			} else {
+				if t, ok := payload["type"].(string); ok && strings.TrimSpace(t) != "" && strings.TrimSpace(t) != "error" {
+					errType = strings.TrimSpace(t)
+				}
+				if m, ok := payload["message"].(string); ok && strings.TrimSpace(m) != "" {
+					message = strings.TrimSpace(m)
+				}
+			}
+		}
+	}
+
+	return errType, message
+}
+
+// buildClaudeErrorBody returns errText verbatim when it is already a complete Anthropic error
+// body, keeping extra fields such as request_id; otherwise it renders an Anthropic error
+// from the upstream type and message.
+func buildClaudeErrorBody(status int, errText string) []byte {
+	trimmed := strings.TrimSpace(errText)
+	if gjson.Valid(trimmed) {
+		root := gjson.Parse(trimmed)
+		if root.Get("type").String() == "error" && root.Get("error.type").Exists() && root.Get("error.message").Exists() {
+			return []byte(trimmed)
+		}
+	}
+	body, err := json.Marshal(newClaudeErrorResponse(status, errText))
+	if err != nil {
+		return []byte(`{"type":"error","error":{"type":"api_error","message":"Internal Server Error"}}`)
+	}
+	return body
+}
+
+// geminiErrorStatusFromStatus maps an HTTP status to the matching Google RPC status name.
+func geminiErrorStatusFromStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "INVALID_ARGUMENT"
+	case http.StatusUnauthorized:
+		return "UNAUTHENTICATED"
+	case http.StatusForbidden:
+		return "PERMISSION_DENIED"
+	case http.StatusNotFound:
+		return "NOT_FOUND"
+	case http.StatusConflict:
+		return "ABORTED"
+	case http.StatusTooManyRequests:
+		return "RESOURCE_EXHAUSTED"
+	case http.StatusNotImplemented:
+		return "UNIMPLEMENTED"
+	case http.StatusServiceUnavailable:
+		return "UNAVAILABLE"
+	case http.StatusGatewayTimeout:
+		return "DEADLINE_EXCEEDED"
+	default:
+		if status >= http.StatusInternalServerError {
+			return "INTERNAL"
+		}
+		return "FAILED_PRECONDITION"
+	}
+}
+
+// buildGeminiErrorBody returns errText unchanged when it is already a Gemini error body,
+// otherwise it wraps the extracted message in {"error":{"code":...,"message":...,"status":...}}.
+func buildGeminiErrorBody(status int, errText string) []byte {
+	trimmed := strings.TrimSpace(errText)
+	if gjson.Valid(trimmed) {
+		root := gjson.Parse(trimmed)
+		if root.Get("error.code").Type == gjson.Number && root.Get("error.status").Exists() {
+			return []byte(trimmed)
+		}
+	}
+
+	body, err := json.Marshal(geminiErrorBody{
+		Error: geminiErrorDetail{
+			Code:    status,
+			Message: extractErrorMessage(errText),
+			Status:  geminiErrorStatusFromStatus(status),
+		},
+	})
+	if err != nil {
+		return []byte(`{"error":{"code":500,"message":"Internal Server Error","status":"INTERNAL"}}`)
+	}
+	return body
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/constant"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+	"github.com/tidwall/gjson"
+)
+
+func TestRenderErrorMessage(t *testing.T) {
+	msg := &interfaces.ErrorMessage{StatusCode: http.StatusNotFound, Error: errors.New("model not found")}
+
+	openai := gjson.ParseBytes(RenderErrorMessage(constant.OpenAI, msg))
+	if got := openai.Get("error.message").String(); got != "model not found" {
+		t.Fatalf("openai error.message = %q", got)
+	}
+	if got := openai.Get("error.code").String(); got != "model_not_found" {
+		t.Fatalf("openai error.code = %q, want model_not_found", got)
+	}
+
+	claude := gjson.ParseBytes(RenderErrorMessage(constant.Claude, msg))
+	if got := claude.Get("type").String(); got != "error" {
+		t.Fatalf("claude type = %q, want error", got)
+	}
+	if got := claude.Get("error.type").String(); got != "not_found_error" {
+		t.Fatalf("claude error.type = %q, want not_found_error", got)
+	}
+	if got := claude.Get("error.message").String(); got != "model not found" {
+		t.Fatalf("claude error.message = %q", got)
+	}
+
+	gemini := gjson.ParseBytes(RenderErrorMessage(constant.Gemini, msg))
+	if got := gemini.Get("error.code").Int(); got != http.StatusNotFound {
+		t.Fatalf("gemini error.code = %d, want %d", got, http.StatusNotFound)
+	}
+	if got := gemini.Get("error.status").String(); got != "NOT_FOUND" {
+		t.Fatalf("gemini error.status = %q, want NOT_FOUND", got)
+	}
+}
+
+func TestRenderErrorMessage_OpenAIFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		errText string
+		wantMsg string
+	}{
+		{
+			name:    "claude envelope",
+			status:  http.StatusTooManyRequests,
+			errText: `{"type":"error","error":{"type":"rate_limit_error","message":"Number of request tokens has exceeded your per-minute rate limit"}}`,
+			wantMsg: "Number of request tokens has exceeded your per-minute rate limit",
+		},
+		{
+			name:    "json without message",
+			status:  http.StatusBadGateway,
+			errText: `{"detail":"upstream unavailable"}`,
+			wantMsg: `{"detail":"upstream unavailable"}`,
+		},
+		{
+			name:    "string error field",
+			status:  http.StatusTooManyRequests,
+			errText: `{"error":"quota exceeded"}`,
+			wantMsg: `{"error":"quota exceeded"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := gjson.ParseBytes(RenderErrorMessage(constant.OpenAI, &interfaces.ErrorMessage{
+				StatusCode: tt.status,
+				Error:      errors.New(tt.errText),
+			}))
+			if body.Get("type").Exists() {
+				t.Fatalf("unexpected top-level type in OpenAI body: %s", body.Raw)
+			}
+			if got := body.Get("error.message").String(); got != tt.wantMsg {
+				t.Fatalf("error.message = %q, want %q", got, tt.wantMsg)
+			}
+			if !body.Get("error.type").Exists() {
+				t.Fatalf("missing error.type: %s", body.Raw)
+			}
+		})
+	}
+
+	for _, upstream := range []string{
+		`{"error":{"message":"bad","type":"invalid_request_error","code":"x"}}`,
+		`{"error":{"message":"bad","code":"context_length_exceeded","param":"messages"}}`,
+		`{"error":{"code":400,"message":"Invalid JSON payload","status":"INVALID_ARGUMENT"}}`,
+	} {
+		got := string(RenderErrorMessage(constant.OpenAI, &interfaces.ErrorMessage{StatusCode: http.StatusBadRequest, Error: errors.New(upstream)}))
+		if got != upstream {
+			t.Fatalf("OpenAI body = %s, want passthrough %s", got, upstream)
+		}
+	}
+}
+
+func TestRenderErrorMessage_NilError(t *testing.T) {
+	for _, format := range []string{constant.OpenAI, constant.Claude, constant.Gemini} {
+		for _, msg := range []*interfaces.ErrorMessage{nil, {StatusCode: http.StatusBadGateway}} {
+			out := RenderErrorMessage(format, msg)
+			if !gjson.ValidBytes(out) {
+				t.Fatalf("%s: invalid JSON %q", format, out)
+			}
+			if got := gjson.GetBytes(out, "error.message").String(); got == "" {
+				t.Fatalf("%s: empty error.message in %s", format, out)
+			}
+		}
+	}
+
+	gemini := gjson.ParseBytes(RenderErrorMessage(constant.Gemini, &interfaces.ErrorMessage{StatusCode: http.StatusBadGateway}))
+	if got := gemini.Get("error.message").String(); got != http.StatusText(http.StatusBadGateway) {
+		t.Fatalf("gemini error.message = %q, want status text", got)
+	}
+	if got := gemini.Get("error.code").Int(); got != http.StatusBadGateway {
+		t.Fatalf("gemini error.code = %d, want %d", got, http.StatusBadGateway)
+	}
+}
@@ -0,0 +1,56 @@
+package gemini
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+	"github.com/router-for-me/CLIProxyAPI/v7/sdk/api/handlers"
+	sdkconfig "github.com/router-for-me/CLIProxyAPI/v7/sdk/config"
+	"github.com/tidwall/gjson"
+)
+
+func TestForwardGeminiStreamTerminalErrorUsesGeminiErrorShape(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	base := handlers.NewBaseAPIHandlers(&sdkconfig.SDKConfig{}, nil)
+	h := NewGeminiAPIHandler(base)
+
+	for _, alt := range []string{"", "json"} {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest(http.MethodPost, "/v1beta/models/gemini-2.5-pro:streamGenerateContent", nil)
+
+		flusher, ok := c.Writer.(http.Flusher)
+		if !ok {
+			t.Fatalf("expected gin writer to implement http.Flusher")
+		}
+
+		data := make(chan []byte)
+		errs := make(chan *interfaces.ErrorMessage, 1)
+		errs <- &interfaces.ErrorMessage{StatusCode: http.StatusTooManyRequests, Error: errors.New("quota exhausted")}
+		close(errs)
+
+		h.forwardGeminiStream(c, flusher, alt, func(error) {}, data, errs)
+		body := recorder.Body.String()
+		payload := body
+		if alt == "" {
+			if !strings.HasPrefix(body, "data: ") || !strings.HasSuffix(body, "\n\n") {
+				t.Fatalf("alt=%q: expected SSE data frame, got: %q", alt, body)
+			}
+			payload = strings.TrimSpace(strings.TrimPrefix(body, "data: "))
+		}
+		if got := gjson.Get(payload, "error.code").Int(); got != http.StatusTooManyRequests {
+			t.Fatalf("alt=%q: error.code = %d, want %d; body=%q", alt, got, http.StatusTooManyRequests, body)
+		}
+		if got := gjson.Get(payload, "error.status").String(); got != "RESOURCE_EXHAUSTED" {
+			t.Fatalf("alt=%q: error.status = %q, want RESOURCE_EXHAUSTED", alt, got)
+		}
+		if got := gjson.Get(payload, "error.message").String(); got != "quota exhausted" {
+			t.Fatalf("alt=%q: error.message = %q", alt, got)
+		}
+	}
+}
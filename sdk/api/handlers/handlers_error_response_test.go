@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -42,6 +43,25 @@ func TestWriteErrorResponse_AddonHeadersDisabledByDefault(t *testing.T) {
 	}
 }
 
+func TestWriteErrorResponse_MatchesStreamErrorShape(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	msg := &interfaces.ErrorMessage{
+		StatusCode: http.StatusTooManyRequests,
+		Error:      errors.New(`{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`),
+	}
+	handler := NewBaseAPIHandlers(nil, nil)
+	handler.WriteErrorResponse(c, msg)
+
+	want := strings.TrimPrefix(strings.SplitN(string(BuildOpenAIErrorSSE(msg)), "\n\n", 2)[0], "data: ")
+	if got := recorder.Body.String(); got != want {
+		t.Fatalf("body = %s, want stream error payload %s", got, want)
+	}
+}
+
 func TestInternalConcurrencyBusyWritesRetryAfterWithoutPassthrough(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	recorder := httptest.NewRecorder()
@@ -168,3 +188,36 @@ func TestEnrichAuthSelectionError_IgnoresOtherErrors(t *testing.T) {
 		t.Fatalf("expected original error to be returned unchanged")
 	}
 }
+
+type retryAfterProviderError struct {
+	retryAfter time.Duration
+}
+
+func (e retryAfterProviderError) Error() string              { return "rate limited" }
+func (e retryAfterProviderError) StatusCode() int            { return http.StatusTooManyRequests }
+func (e retryAfterProviderError) RetryAfter() *time.Duration { return &e.retryAfter }
+func (e retryAfterProviderError) Provider() string           { return "claude" }
+
+func TestExecutionErrorMessage_PopulatesRetryAfterAndProvider(t *testing.T) {
+	msg := executionErrorMessage(retryAfterProviderError{retryAfter: 12 * time.Second})
+
+	if msg.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", msg.StatusCode, http.StatusTooManyRequests)
+	}
+	if msg.RetryAfter != 12*time.Second {
+		t.Fatalf("RetryAfter = %v, want 12s", msg.RetryAfter)
+	}
+	if msg.Provider != "claude" {
+		t.Fatalf("Provider = %q, want claude", msg.Provider)
+	}
+
+	wrapped := executionErrorMessage(fmt.Errorf("execute: %w", retryAfterProviderError{retryAfter: 12 * time.Second}))
+	if wrapped.StatusCode != http.StatusTooManyRequests || wrapped.RetryAfter != 12*time.Second {
+		t.Fatalf("wrapped error = %d / %v, want %d / 12s", wrapped.StatusCode, wrapped.RetryAfter, http.StatusTooManyRequests)
+	}
+
+	plain := executionErrorMessage(errors.New("boom"))
+	if plain.RetryAfter != 0 || plain.Provider != "" {
+		t.Fatalf("plain error should leave hints empty, got %v / %q", plain.RetryAfter, plain.Provider)
+	}
+}
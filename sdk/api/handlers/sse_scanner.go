@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// sseScannerMaxTokenSize bounds a single SSE line, matching the executors' stream scanners.
+const sseScannerMaxTokenSize = 52_428_800 // 50MB
+
+// SSEEvent is one dispatched Server-Sent Events frame.
+type SSEEvent struct {
+	// Event is the value of the `event:` field, empty for unnamed events.
+	Event string
+
+	// Data holds the `data:` lines of the frame joined with "\n".
+	Data []byte
+}
+
+// SSEScanner tokenizes an SSE stream into event/data frames.
+// It accepts LF and CRLF line endings, joins multi-line data and skips comment lines.
+type SSEScanner struct {
+	scanner *bufio.Scanner
+	current SSEEvent
+}
+
+// NewSSEScanner returns an SSEScanner reading from r.
+func NewSSEScanner(r io.Reader) *SSEScanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, sseScannerMaxTokenSize)
+	return &SSEScanner{scanner: scanner}
+}
+
+// Scan advances to the next frame, reporting false at EOF or on a read error.
+// A trailing frame without a terminating blank line is still dispatched at EOF.
+func (s *SSEScanner) Scan() bool {
+	var (
+		event   string
+		data    bytes.Buffer
+		hasData bool
+		pending bool
+	)
+	for s.scanner.Scan() {
+		line := s.scanner.Bytes()
+		if len(line) == 0 {
+			if pending {
+				s.current = SSEEvent{Event: event, Data: data.Bytes()}
+				return true
+			}
+			continue
+		}
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value := line, []byte(nil)
+		if idx := bytes.IndexByte(line, ':'); idx >= 0 {
+			field = line[:idx]
+			value = bytes.TrimPrefix(line[idx+1:], []byte(" "))
+		}
+		switch string(field) {
+		case "event":
+			event = string(value)
+			pending = true
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.Write(value)
+			hasData = true
+			pending = true
+		}
+	}
+	if pending {
+		s.current = SSEEvent{Event: event, Data: data.Bytes()}
+		return true
+	}
+	return false
+}
+
+// Event returns the frame produced by the last successful call to Scan.
+func (s *SSEScanner) Event() SSEEvent {
+	return s.current
+}
+
+// Err returns the first non-EOF read error encountered by the scanner.
+func (s *SSEScanner) Err() error {
+	return s.scanner.Err()
+}
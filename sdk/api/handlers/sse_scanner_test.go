@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+func scanAllSSE(t *testing.T, input string) []SSEEvent {
+	t.Helper()
+	scanner := NewSSEScanner(strings.NewReader(input))
+	var events []SSEEvent
+	for scanner.Scan() {
+		events = append(events, scanner.Event())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	return events
+}
+
+func TestSSEScanner_MultiLineDataAndComments(t *testing.T) {
+	input := ": keepalive\n\n" +
+		"data: {\"a\":1,\n" +
+		"data: \"b\":2}\n\n" +
+		"data: [DONE]\n\n"
+
+	events := scanAllSSE(t, input)
+	if len(events) != 2 {
+		t.Fatalf("event count = %d, want 2: %+v", len(events), events)
+	}
+	if got := string(events[0].Data); got != "{\"a\":1,\n\"b\":2}" {
+		t.Fatalf("first data = %q", got)
+	}
+	if events[0].Event != "" {
+		t.Fatalf("first event name = %q, want empty", events[0].Event)
+	}
+	if got := string(events[1].Data); got != "[DONE]" {
+		t.Fatalf("second data = %q, want [DONE]", got)
+	}
+}
+
+func TestSSEScanner_ErrorEventWithCRLF(t *testing.T) {
+	input := "event: message_start\r\ndata: {\"type\":\"message_start\"}\r\n\r\n" +
+		"event: error\r\ndata: {\"type\":\"error\",\"error\":{\"type\":\"invalid_request_error\",\"message\":\"Invalid signature in thinking block\"}}"
+
+	events := scanAllSSE(t, input)
+	if len(events) != 2 {
+		t.Fatalf("event count = %d, want 2: %+v", len(events), events)
+	}
+	if events[0].Event != "message_start" {
+		t.Fatalf("first event = %q, want message_start", events[0].Event)
+	}
+	if events[1].Event != "error" {
+		t.Fatalf("second event = %q, want error", events[1].Event)
+	}
+	if !isThinkingSignatureErrorText(string(events[1].Data)) {
+		t.Fatalf("error frame data should carry the thinking signature error: %q", events[1].Data)
+	}
+}
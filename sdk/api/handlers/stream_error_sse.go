@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"bytes"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+)
+
+// BuildOpenAIErrorSSE builds the terminal frames for a failed OpenAI chat completions stream:
+// an OpenAI-style error object followed by `data: [DONE]`.
+func BuildOpenAIErrorSSE(msg *interfaces.ErrorMessage) []byte {
+	status, errText := errorMessageStatusText(msg)
+	body := buildOpenAIErrorBody(status, errText)
+
+	var buf bytes.Buffer
+	buf.WriteString("data: ")
+	buf.Write(body)
+	buf.WriteString("\n\n")
+	buf.WriteString("data: [DONE]\n\n")
+	return buf.Bytes()
+}
+
+// BuildClaudeErrorSSE builds an Anthropic `event: error` frame for a Claude messages stream
+// that fails after message_start has been sent.
+func BuildClaudeErrorSSE(msg *interfaces.ErrorMessage) []byte {
+	status, errText := errorMessageStatusText(msg)
+	body := buildClaudeErrorBody(status, errText)
+
+	var buf bytes.Buffer
+	buf.WriteString("event: error\ndata: ")
+	buf.Write(body)
+	buf.WriteString("\n\n")
+	return buf.Bytes()
+}
+
+// BuildGeminiErrorSSE builds the terminal error payload for a failed Gemini stream.
+// With an empty alt it returns an SSE `data:` frame; any other alt (e.g. "json")
+// returns the bare Gemini error JSON, matching the raw streaming mode.
+func BuildGeminiErrorSSE(msg *interfaces.ErrorMessage, alt string) []byte {
+	status, errText := errorMessageStatusText(msg)
+	body := buildGeminiErrorBody(status, errText)
+	if alt != "" {
+		return body
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("data: ")
+	buf.Write(body)
+	buf.WriteString("\n\n")
+	return buf.Bytes()
+}
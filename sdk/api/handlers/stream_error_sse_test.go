@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v7/internal/interfaces"
+	"github.com/tidwall/gjson"
+)
+
+func sseDataPayloads(t *testing.T, frames string) []string {
+	t.Helper()
+	var payloads []string
+	for _, frame := range strings.Split(strings.TrimSpace(frames), "\n\n") {
+		for _, line := range strings.Split(frame, "\n") {
+			if strings.HasPrefix(line, "data: ") {
+				payloads = append(payloads, strings.TrimPrefix(line, "data: "))
+			}
+		}
+	}
+	return payloads
+}
+
+func TestBuildOpenAIErrorSSE_StructuredError(t *testing.T) {
+	upstream := `{"error":{"message":"model overloaded","type":"server_error","code":"overloaded"}}`
+	out := string(BuildOpenAIErrorSSE(&interfaces.ErrorMessage{
+		StatusCode: http.StatusServiceUnavailable,
+		Error:      errors.New(upstream),
+	}))
+
+	payloads := sseDataPayloads(t, out)
+	if len(payloads) != 2 {
+		t.Fatalf("payload count = %d, want 2: %q", len(payloads), out)
+	}
+	if payloads[0] != upstream {
+		t.Fatalf("error payload = %s, want upstream body %s", payloads[0], upstream)
+	}
+	if payloads[1] != "[DONE]" {
+		t.Fatalf("last payload = %q, want [DONE]", payloads[1])
+	}
+}
+
+func TestBuildOpenAIErrorSSE_PlainError(t *testing.T) {
+	out := string(BuildOpenAIErrorSSE(&interfaces.ErrorMessage{
+		StatusCode: http.StatusTooManyRequests,
+		Error:      errors.New("quota exhausted"),
+	}))
+
+	payloads := sseDataPayloads(t, out)
+	if len(payloads) != 2 || payloads[1] != "[DONE]" {
+		t.Fatalf("unexpected frames: %q", out)
+	}
+	body := gjson.Parse(payloads[0])
+	if got := body.Get("error.message").String(); got != "quota exhausted" {
+		t.Fatalf("error.message = %q, want %q", got, "quota exhausted")
+	}
+	if got := body.Get("error.type").String(); got != "rate_limit_error" {
+		t.Fatalf("error.type = %q, want %q", got, "rate_limit_error")
+	}
+}
+
+func TestBuildClaudeErrorSSE_KeepsAnthropicJSON(t *testing.T) {
+	upstream := `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`
+	out := string(BuildClaudeErrorSSE(&interfaces.ErrorMessage{
+		StatusCode: 529,
+		Error:      errors.New(upstream),
+	}))
+
+	if !strings.HasPrefix(out, "event: error\n") {
+		t.Fatalf("frame should start with event: error, got %q", out)
+	}
+	payloads := sseDataPayloads(t, out)
+	if len(payloads) != 1 || payloads[0] != upstream {
+		t.Fatalf("payloads = %q, want upstream body %s", payloads, upstream)
+	}
+}
+
+func TestBuildClaudeErrorSSE_PassesThroughRequestID(t *testing.T) {
+	upstream := `{"type":"error","error":{"type":"rate_limit_error","message":"slow down"},"request_id":"req_123"}`
+	payloads := sseDataPayloads(t, string(BuildClaudeErrorSSE(&interfaces.ErrorMessage{
+		StatusCode: http.StatusTooManyRequests,
+		Error:      errors.New(upstream),
+	})))
+	if len(payloads) != 1 || payloads[0] != upstream {
+		t.Fatalf("payloads = %q, want verbatim upstream body %s", payloads, upstream)
+	}
+}
+
+func TestBuildClaudeErrorSSE_MatchesHandlerErrorShape(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		errText  string
+		wantType string
+		wantMsg  string
+	}{
+		{
+			name:     "upstream type wins over status",
+			status:   http.StatusInternalServerError,
+			errText:  `{"error":{"type":"overloaded_error","message":"busy"}}`,
+			wantType: "overloaded_error",
+			wantMsg:  "busy",
+		},
+		{
+			name:     "code used when message missing",
+			status:   http.StatusBadRequest,
+			errText:  `{"error":{"code":"x"}}`,
+			wantType: "invalid_request_error",
+			wantMsg:  "x",
+		},
+		{
+			name:     "missing error type filled from status",
+			status:   http.StatusTooManyRequests,
+			errText:  `{"type":"error","error":{"message":"slow"}}`,
+			wantType: "rate_limit_error",
+			wantMsg:  "slow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payloads := sseDataPayloads(t, string(BuildClaudeErrorSSE(&interfaces.ErrorMessage{
+				StatusCode: tt.status,
+				Error:      errors.New(tt.errText),
+			})))
+			if len(payloads) != 1 {
+				t.Fatalf("payload count = %d, want 1", len(payloads))
+			}
+			body := gjson.Parse(payloads[0])
+			if got := body.Get("error.type").String(); got != tt.wantType {
+				t.Fatalf("error.type = %q, want %q", got, tt.wantType)
+			}
+			if got := body.Get("error.message").String(); got != tt.wantMsg {
+				t.Fatalf("error.message = %q, want %q", got, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestBuildClaudeErrorSSE_WrapsPlainText(t *testing.T) {
+	out := string(BuildClaudeErrorSSE(&interfaces.ErrorMessage{
+		StatusCode: http.StatusTooManyRequests,
+		Error:      errors.New("slow down"),
+	}))
+
+	payloads := sseDataPayloads(t, out)
+	if len(payloads) != 1 {
+		t.Fatalf("payload count = %d, want 1: %q", len(payloads), out)
+	}
+	body := gjson.Parse(payloads[0])
+	if got := body.Get("type").String(); got != "error" {
+		t.Fatalf("type = %q, want error", got)
+	}
+	if got := body.Get("error.type").String(); got != "rate_limit_error" {
+		t.Fatalf("error.type = %q, want rate_limit_error", got)
+	}
+	if got := body.Get("error.message").String(); got != "slow down" {
+		t.Fatalf("error.message = %q, want %q", got, "slow down")
+	}
+}
+
+func TestBuildGeminiErrorSSE_SSEMode(t *testing.T) {
+	out := string(BuildGeminiErrorSSE(&interfaces.ErrorMessage{
+		StatusCode: http.StatusTooManyRequests,
+		Error:      errors.New("quota exhausted"),
+	}, ""))
+
+	payloads := sseDataPayloads(t, out)
+	if len(payloads) != 1 {
+		t.Fatalf("payload count = %d, want 1: %q", len(payloads), out)
+	}
+	body := gjson.Parse(payloads[0])
+	if got := body.Get("error.code").Int(); got != http.StatusTooManyRequests {
+		t.Fatalf("error.code = %d, want %d", got, http.StatusTooManyRequests)
+	}
+	if got := body.Get("error.status").String(); got != "RESOURCE_EXHAUSTED" {
+		t.Fatalf("error.status = %q, want RESOURCE_EXHAUSTED", got)
+	}
+	if got := body.Get("error.message").String(); got != "quota exhausted" {
+		t.Fatalf("error.message = %q, want %q", got, "quota exhausted")
+	}
+}
+
+func TestBuildGeminiErrorSSE_RawMode(t *testing.T) {
+	upstream := `{"error":{"code":400,"message":"bad request","status":"INVALID_ARGUMENT"}}`
+	out := string(BuildGeminiErrorSSE(&interfaces.ErrorMessage{
+		StatusCode: http.StatusBadRequest,
+		Error:      errors.New(upstream),
+	}, "json"))
+
+	if out != upstream {
+		t.Fatalf("raw output = %s, want upstream body %s", out, upstream)
+	}
+}